@@ -0,0 +1,188 @@
+// Package agents provides pluggable metadata enrichment for artists and albums,
+// sourced from external services such as MusicBrainz, Last.fm and Spotify.
+package agents
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// negativeCacheTTL is how long a "no provider had anything" result is
+// memoized for, so a request that comes in during a transient outage (all
+// providers briefly rate-limited or timing out) doesn't get a 30-day-stale
+// empty result baked in - just a short one that self-heals on the next try.
+const negativeCacheTTL = 10 * time.Minute
+
+// ArtistInfo is the metadata an agent can contribute about an artist.
+type ArtistInfo struct {
+	MBID           string
+	Biography      string
+	LastFMUrl      string
+	SmallImageUrl  string
+	MediumImageUrl string
+	LargeImageUrl  string
+	SimilarArtists []SimilarArtist
+}
+
+// SimilarArtist is a related artist, optionally already known to the library.
+type SimilarArtist struct {
+	Name string
+	MBID string
+}
+
+// AlbumInfo is the metadata an agent can contribute about an album.
+type AlbumInfo struct {
+	MBID           string
+	Description    string
+	SmallImageUrl  string
+	MediumImageUrl string
+	LargeImageUrl  string
+}
+
+// Agent is implemented by any external metadata provider. A provider only
+// needs to implement the sub-interfaces below that it can actually satisfy;
+// Agents probes for them with type assertions.
+type Agent interface {
+	AgentName() string
+}
+
+type ArtistInfoAgent interface {
+	Agent
+	GetArtistInfo(ctx context.Context, id, name, mbid string) (*ArtistInfo, error)
+}
+
+type AlbumInfoAgent interface {
+	Agent
+	GetAlbumInfo(ctx context.Context, id, name, artist, mbid string) (*AlbumInfo, error)
+}
+
+// Agents aggregates all configured providers and merges their results,
+// taking the first non-empty value for each field in provider order.
+type Agents struct {
+	providers []Agent
+	cache     *Cache
+}
+
+// New builds an Agents aggregator from the given providers, tried in order.
+// Results are memoized in cache, which may be nil to disable caching.
+func New(cache *Cache, providers ...Agent) *Agents {
+	return &Agents{providers: providers, cache: cache}
+}
+
+func (a *Agents) GetArtistInfo(ctx context.Context, id, name, mbid string) (*ArtistInfo, error) {
+	key := "artist:" + id
+	if a.cache != nil {
+		var cached ArtistInfo
+		if a.cache.Get(key, &cached) {
+			return &cached, nil
+		}
+	}
+	return a.RefreshArtistInfo(ctx, id, name, mbid)
+}
+
+// RefreshArtistInfo re-queries every provider and re-populates the cache,
+// bypassing any cached entry for id. Refresher calls this instead of
+// GetArtistInfo so a periodic refresh actually refreshes, rather than
+// hitting the cache it warmed on its first tick and becoming a no-op for the
+// rest of the cache's TTL.
+func (a *Agents) RefreshArtistInfo(ctx context.Context, id, name, mbid string) (*ArtistInfo, error) {
+	key := "artist:" + id
+	info := &ArtistInfo{}
+	for _, p := range a.providers {
+		ap, ok := p.(ArtistInfoAgent)
+		if !ok {
+			continue
+		}
+		pi, err := ap.GetArtistInfo(ctx, id, name, mbid)
+		if err != nil {
+			continue
+		}
+		mergeArtistInfo(info, pi)
+	}
+
+	if a.cache != nil {
+		a.cache.Put(key, *info, cacheTTLFor(reflect.DeepEqual(*info, ArtistInfo{})))
+	}
+	return info, nil
+}
+
+func (a *Agents) GetAlbumInfo(ctx context.Context, id, name, artist, mbid string) (*AlbumInfo, error) {
+	key := "album:" + id
+	if a.cache != nil {
+		var cached AlbumInfo
+		if a.cache.Get(key, &cached) {
+			return &cached, nil
+		}
+	}
+
+	info := &AlbumInfo{}
+	for _, p := range a.providers {
+		ap, ok := p.(AlbumInfoAgent)
+		if !ok {
+			continue
+		}
+		pi, err := ap.GetAlbumInfo(ctx, id, name, artist, mbid)
+		if err != nil {
+			continue
+		}
+		mergeAlbumInfo(info, pi)
+	}
+
+	if a.cache != nil {
+		a.cache.Put(key, *info, cacheTTLFor(reflect.DeepEqual(*info, AlbumInfo{})))
+	}
+	return info, nil
+}
+
+// cacheTTLFor picks the cache lifetime for a GetArtistInfo/GetAlbumInfo
+// result: a long TTL for a real, provider-sourced result, a short one when
+// every provider failed or had nothing, so that case doesn't get stuck.
+func cacheTTLFor(empty bool) time.Duration {
+	if empty {
+		return negativeCacheTTL
+	}
+	return 30 * 24 * time.Hour
+}
+
+func mergeArtistInfo(dst *ArtistInfo, src *ArtistInfo) {
+	if dst.MBID == "" {
+		dst.MBID = src.MBID
+	}
+	if dst.Biography == "" {
+		dst.Biography = src.Biography
+	}
+	if dst.LastFMUrl == "" {
+		dst.LastFMUrl = src.LastFMUrl
+	}
+	if dst.SmallImageUrl == "" {
+		dst.SmallImageUrl = src.SmallImageUrl
+	}
+	if dst.MediumImageUrl == "" {
+		dst.MediumImageUrl = src.MediumImageUrl
+	}
+	if dst.LargeImageUrl == "" {
+		dst.LargeImageUrl = src.LargeImageUrl
+	}
+	if len(dst.SimilarArtists) == 0 {
+		dst.SimilarArtists = src.SimilarArtists
+	}
+}
+
+func mergeAlbumInfo(dst *AlbumInfo, src *AlbumInfo) {
+	if dst.MBID == "" {
+		dst.MBID = src.MBID
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if dst.SmallImageUrl == "" {
+		dst.SmallImageUrl = src.SmallImageUrl
+	}
+	if dst.MediumImageUrl == "" {
+		dst.MediumImageUrl = src.MediumImageUrl
+	}
+	if dst.LargeImageUrl == "" {
+		dst.LargeImageUrl = src.LargeImageUrl
+	}
+}
@@ -0,0 +1,114 @@
+package agents
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/deluan/navidrome/log"
+)
+
+// Cache is a simple disk-backed cache with per-entry TTL, used to avoid
+// hammering external providers for data that rarely changes. It keeps an
+// in-memory copy of each entry for fast repeated lookups within a process
+// lifetime, and persists entries to disk so they survive restarts.
+type Cache struct {
+	dir string
+	mu  sync.RWMutex
+	mem map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	Value     json.RawMessage `json:"value"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+}
+
+// NewCache creates a Cache that persists entries as files under dir.
+// dir is created if it does not yet exist.
+func NewCache(dir string) *Cache {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Error("Could not create agents cache dir", "dir", dir, err)
+	}
+	return &Cache{dir: dir, mem: map[string]cacheEntry{}}
+}
+
+// Get unmarshals the cached value for key into dest, if present and not
+// expired. dest must be a pointer to the same type that was passed to Put,
+// so callers get back the concrete type instead of a generic map.
+func (c *Cache) Get(key string, dest interface{}) bool {
+	c.mu.RLock()
+	entry, ok := c.mem[key]
+	c.mu.RUnlock()
+	if !ok {
+		entry, ok = c.loadFromDisk(key)
+		if !ok {
+			return false
+		}
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		return false
+	}
+	if err := json.Unmarshal(entry.Value, dest); err != nil {
+		return false
+	}
+	return true
+}
+
+// Put stores value under key, expiring it after ttl.
+func (c *Cache) Put(key string, value interface{}, ttl time.Duration) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		log.Error("Could not marshal agents cache entry", "key", key, err)
+		return
+	}
+	entry := cacheEntry{Value: raw, ExpiresAt: time.Now().Add(ttl)}
+
+	c.mu.Lock()
+	c.mem[key] = entry
+	c.mu.Unlock()
+
+	c.saveToDisk(key, entry)
+}
+
+func (c *Cache) loadFromDisk(key string) (cacheEntry, bool) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+	c.mu.Lock()
+	c.mem[key] = entry
+	c.mu.Unlock()
+	return entry, true
+}
+
+func (c *Cache) saveToDisk(key string, entry cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := ioutil.WriteFile(c.path(key), data, 0644); err != nil {
+		log.Error("Could not persist agents cache entry", "key", key, err)
+	}
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, cacheFileName(key))
+}
+
+func cacheFileName(key string) string {
+	safe := make([]rune, 0, len(key))
+	for _, r := range key {
+		if r == '/' || r == ':' || r == '\\' {
+			r = '_'
+		}
+		safe = append(safe, r)
+	}
+	return string(safe) + ".json"
+}
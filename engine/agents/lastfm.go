@@ -0,0 +1,111 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/deluan/navidrome/log"
+)
+
+const lastFMBaseUrl = "https://ws.audioscrobbler.com/2.0/"
+
+// LastFMAgent provides artist biographies, similar artists and cover art
+// sourced from the Last.fm API. It requires an API key obtained from
+// https://www.last.fm/api/account/create.
+type LastFMAgent struct {
+	apiKey string
+	client *http.Client
+}
+
+func NewLastFMAgent(apiKey string) *LastFMAgent {
+	return &LastFMAgent{apiKey: apiKey, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (a *LastFMAgent) AgentName() string {
+	return "lastfm"
+}
+
+func (a *LastFMAgent) GetArtistInfo(ctx context.Context, id, name, mbid string) (*ArtistInfo, error) {
+	var resp lastFMArtistInfoResponse
+	if err := a.call(ctx, "artist.getinfo", name, mbid, &resp); err != nil {
+		return nil, err
+	}
+
+	info := &ArtistInfo{
+		MBID:      resp.Artist.Mbid,
+		Biography: resp.Artist.Bio.Summary,
+		LastFMUrl: resp.Artist.Url,
+	}
+	for _, img := range resp.Artist.Image {
+		assignImage(info, img.Size, img.Text)
+	}
+	for _, s := range resp.Artist.Similar.Artist {
+		info.SimilarArtists = append(info.SimilarArtists, SimilarArtist{Name: s.Name, MBID: s.Mbid})
+	}
+	return info, nil
+}
+
+func assignImage(info *ArtistInfo, size, u string) {
+	switch size {
+	case "medium":
+		info.SmallImageUrl = u
+	case "large":
+		info.MediumImageUrl = u
+	case "extralarge", "mega":
+		info.LargeImageUrl = u
+	}
+}
+
+func (a *LastFMAgent) call(ctx context.Context, method, artist, mbid string, out interface{}) error {
+	if a.apiKey == "" {
+		return fmt.Errorf("lastfm: no API key configured")
+	}
+	q := url.Values{}
+	q.Set("method", method)
+	q.Set("api_key", a.apiKey)
+	q.Set("format", "json")
+	if mbid != "" {
+		q.Set("mbid", mbid)
+	} else {
+		q.Set("artist", artist)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lastFMBaseUrl+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		log.Warn(ctx, "Last.fm request failed", "method", method, err)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("lastfm returned status %d for %s", resp.StatusCode, method)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+type lastFMArtistInfoResponse struct {
+	Artist struct {
+		Mbid string `json:"mbid"`
+		Url  string `json:"url"`
+		Bio  struct {
+			Summary string `json:"summary"`
+		} `json:"bio"`
+		Image []struct {
+			Text string `json:"#text"`
+			Size string `json:"size"`
+		} `json:"image"`
+		Similar struct {
+			Artist []struct {
+				Name string `json:"name"`
+				Mbid string `json:"mbid"`
+			} `json:"artist"`
+		} `json:"similar"`
+	} `json:"artist"`
+}
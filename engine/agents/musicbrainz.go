@@ -0,0 +1,102 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/deluan/navidrome/log"
+)
+
+const musicBrainzBaseUrl = "https://musicbrainz.org/ws/2"
+
+// MusicBrainzAgent resolves an artist's MBID (searching by name when none is
+// given) and Last.fm URL from the MusicBrainz web service. It provides no
+// similar artists and implements no album lookup.
+type MusicBrainzAgent struct {
+	client *http.Client
+}
+
+func NewMusicBrainzAgent() *MusicBrainzAgent {
+	return &MusicBrainzAgent{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (a *MusicBrainzAgent) AgentName() string {
+	return "musicbrainz"
+}
+
+func (a *MusicBrainzAgent) GetArtistInfo(ctx context.Context, id, name, mbid string) (*ArtistInfo, error) {
+	if mbid == "" {
+		found, err := a.searchArtistMBID(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		mbid = found
+	}
+	if mbid == "" {
+		return &ArtistInfo{}, nil
+	}
+
+	var resp mbArtistResponse
+	path := fmt.Sprintf("/artist/%s?inc=url-rels&fmt=json", url.PathEscape(mbid))
+	if err := a.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+
+	info := &ArtistInfo{MBID: mbid}
+	for _, rel := range resp.Relations {
+		if rel.Type == "last.fm" {
+			info.LastFMUrl = rel.Url.Resource
+		}
+	}
+	return info, nil
+}
+
+func (a *MusicBrainzAgent) searchArtistMBID(ctx context.Context, name string) (string, error) {
+	var resp mbArtistSearchResponse
+	path := fmt.Sprintf("/artist?query=%s&fmt=json&limit=1", url.QueryEscape(name))
+	if err := a.get(ctx, path, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Artists) == 0 {
+		return "", nil
+	}
+	return resp.Artists[0].Id, nil
+}
+
+func (a *MusicBrainzAgent) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, musicBrainzBaseUrl+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "Navidrome/1.0 (+https://navidrome.org)")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		log.Warn(ctx, "MusicBrainz request failed", "path", path, err)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("musicbrainz returned status %d for %s", resp.StatusCode, path)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+type mbArtistSearchResponse struct {
+	Artists []struct {
+		Id string `json:"id"`
+	} `json:"artists"`
+}
+
+type mbArtistResponse struct {
+	Relations []struct {
+		Type string `json:"type"`
+		Url  struct {
+			Resource string `json:"resource"`
+		} `json:"url"`
+	} `json:"relations"`
+}
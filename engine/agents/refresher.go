@@ -0,0 +1,53 @@
+package agents
+
+import (
+	"context"
+	"time"
+
+	"github.com/deluan/navidrome/log"
+	"github.com/deluan/navidrome/model"
+)
+
+// Refresher periodically walks the library's artists and pre-warms the
+// Agents cache with their artist info, so Subsonic clients don't pay the
+// external-lookup latency on a first getArtistInfo request. Album info is
+// not pre-fetched.
+type Refresher struct {
+	ds     model.DataStore
+	agents *Agents
+	period time.Duration
+}
+
+func NewRefresher(ds model.DataStore, agents *Agents, period time.Duration) *Refresher {
+	return &Refresher{ds: ds, agents: agents, period: period}
+}
+
+// Run blocks, refreshing the cache every period, until ctx is cancelled.
+func (r *Refresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.period)
+	defer ticker.Stop()
+
+	r.refreshAll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refreshAll(ctx)
+		}
+	}
+}
+
+func (r *Refresher) refreshAll(ctx context.Context) {
+	artists, err := r.ds.Artist().GetAll()
+	if err != nil {
+		log.Error(ctx, "Could not load artists for metadata refresh", err)
+		return
+	}
+
+	for _, a := range artists {
+		if _, err := r.agents.RefreshArtistInfo(ctx, a.ID, a.Name, ""); err != nil {
+			log.Warn(ctx, "Could not refresh artist metadata", "artist", a.Name, err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deluan/navidrome/log"
+)
+
+const (
+	spotifyAuthUrl = "https://accounts.spotify.com/api/token"
+	spotifyApiUrl  = "https://api.spotify.com/v1"
+)
+
+// SpotifyAgent sources high-resolution artist and album cover art from the
+// Spotify Web API, using the client-credentials flow (no user login).
+type SpotifyAgent struct {
+	clientId     string
+	clientSecret string
+	client       *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func NewSpotifyAgent(clientId, clientSecret string) *SpotifyAgent {
+	return &SpotifyAgent{
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (a *SpotifyAgent) AgentName() string {
+	return "spotify"
+}
+
+func (a *SpotifyAgent) GetArtistInfo(ctx context.Context, id, name, mbid string) (*ArtistInfo, error) {
+	var resp spotifySearchResponse
+	if err := a.get(ctx, "/search?type=artist&limit=1&q="+url.QueryEscape(name), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Artists.Items) == 0 {
+		return &ArtistInfo{}, nil
+	}
+
+	info := &ArtistInfo{}
+	assignSpotifyImages(&info.SmallImageUrl, &info.MediumImageUrl, &info.LargeImageUrl, resp.Artists.Items[0].Images)
+	return info, nil
+}
+
+func (a *SpotifyAgent) GetAlbumInfo(ctx context.Context, id, name, artist, mbid string) (*AlbumInfo, error) {
+	var resp spotifyAlbumSearchResponse
+	q := fmt.Sprintf("album:%s artist:%s", name, artist)
+	if err := a.get(ctx, "/search?type=album&limit=1&q="+url.QueryEscape(q), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Albums.Items) == 0 {
+		return &AlbumInfo{}, nil
+	}
+
+	info := &AlbumInfo{}
+	assignSpotifyImages(&info.SmallImageUrl, &info.MediumImageUrl, &info.LargeImageUrl, resp.Albums.Items[0].Images)
+	return info, nil
+}
+
+// assignSpotifyImages maps Spotify's size-sorted (largest-first) image list
+// onto our small/medium/large slots.
+func assignSpotifyImages(small, medium, large *string, images []spotifyImage) {
+	for i, img := range images {
+		switch i {
+		case 0:
+			*large = img.Url
+		case 1:
+			*medium = img.Url
+		case 2:
+			*small = img.Url
+		}
+	}
+}
+
+func (a *SpotifyAgent) get(ctx context.Context, path string, out interface{}) error {
+	token, err := a.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, spotifyApiUrl+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		log.Warn(ctx, "Spotify request failed", "path", path, err)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("spotify returned status %d for %s", resp.StatusCode, path)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (a *SpotifyAgent) token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.accessToken != "" && time.Now().Before(a.expiresAt) {
+		return a.accessToken, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, spotifyAuthUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(a.clientId, a.clientSecret)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("spotify auth returned status %d", resp.StatusCode)
+	}
+
+	var auth struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
+		return "", err
+	}
+
+	a.accessToken = auth.AccessToken
+	a.expiresAt = time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	return a.accessToken, nil
+}
+
+type spotifyImage struct {
+	Url string `json:"url"`
+}
+
+type spotifySearchResponse struct {
+	Artists struct {
+		Items []struct {
+			Images []spotifyImage `json:"images"`
+		} `json:"items"`
+	} `json:"artists"`
+}
+
+type spotifyAlbumSearchResponse struct {
+	Albums struct {
+		Items []struct {
+			Images []spotifyImage `json:"images"`
+		} `json:"items"`
+	} `json:"albums"`
+}
@@ -3,11 +3,13 @@ package engine
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/deluan/navidrome/engine/agents"
 	"github.com/deluan/navidrome/log"
 	"github.com/deluan/navidrome/model"
 	"github.com/deluan/navidrome/utils"
@@ -21,14 +23,55 @@ type Browser interface {
 	Album(ctx context.Context, id string) (*DirectoryInfo, error)
 	GetSong(ctx context.Context, id string) (*Entry, error)
 	GetGenres(ctx context.Context) (model.Genres, error)
+	ArtistInfo(ctx context.Context, id string) (*ArtistInfo, error)
+	AlbumInfo(ctx context.Context, id string) (*AlbumInfo, error)
+	SimilarSongs(ctx context.Context, id string, count int) (Entries, error)
+	GetPlaylists(ctx context.Context) ([]PlaylistInfo, error)
+	GetPlaylist(ctx context.Context, id string) (*DirectoryInfo, error)
+	InvalidateSong(ctx context.Context, id string) error
+	Cache() *DirectoryCache
 }
 
-func NewBrowser(ds model.DataStore) Browser {
-	return &browser{ds}
+func NewBrowser(ds model.DataStore, ag *agents.Agents, cacheSize int, cacheTTL time.Duration, bus *model.ScanEventBus) Browser {
+	cache := NewDirectoryCache(cacheSize, cacheTTL, bus)
+	return &browser{ds, ag, NewSmartPlaylistCompiler(ds), cache}
 }
 
 type browser struct {
-	ds model.DataStore
+	ds            model.DataStore
+	agents        *agents.Agents
+	smartCompiler *SmartPlaylistCompiler
+	cache         *DirectoryCache
+}
+
+// Cache exposes the directory cache so it can be wired into the
+// /admin/metrics Prometheus endpoint.
+func (b *browser) Cache() *DirectoryCache {
+	return b.cache
+}
+
+// ArtistInfo is the enriched, externally-sourced metadata returned by
+// getArtistInfo: biography, similar artists and cover art.
+type ArtistInfo struct {
+	Id             string
+	Biography      string
+	MusicBrainzId  string
+	LastFmUrl      string
+	SmallImageUrl  string
+	MediumImageUrl string
+	LargeImageUrl  string
+	SimilarArtist  []DirectoryInfo
+}
+
+// AlbumInfo is the enriched, externally-sourced metadata returned by
+// getAlbumInfo: description and cover art.
+type AlbumInfo struct {
+	Id             string
+	Notes          string
+	MusicBrainzId  string
+	SmallImageUrl  string
+	MediumImageUrl string
+	LargeImageUrl  string
 }
 
 func (b *browser) MediaFolders(ctx context.Context) (model.MediaFolders, error) {
@@ -72,44 +115,67 @@ type DirectoryInfo struct {
 }
 
 func (b *browser) Artist(ctx context.Context, id string) (*DirectoryInfo, error) {
+	userID := getUserID(ctx)
+	cacheKey := userID + ":artist:" + id
+	if dir, ok := b.cache.Get(cacheKey); ok {
+		return dir, nil
+	}
+
 	a, albums, err := b.retrieveArtist(id)
 	if err != nil {
 		return nil, err
 	}
 	log.Debug(ctx, "Found Artist", "id", id, "name", a.Name)
+
 	var albumIds []string
 	for _, al := range albums {
 		albumIds = append(albumIds, al.ID)
 	}
-	annMap, err := b.ds.Annotation().GetMap(getUserID(ctx), model.AlbumItemType, albumIds)
-	return b.buildArtistDir(a, albums, annMap), nil
+	annMap, err := b.ds.Annotation().GetMap(userID, model.AlbumItemType, albumIds)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := b.buildArtistDir(a, albums, annMap)
+	b.cache.Put(cacheKey, dir)
+	return dir, nil
 }
 
 func (b *browser) Album(ctx context.Context, id string) (*DirectoryInfo, error) {
+	userID := getUserID(ctx)
+	cacheKey := userID + ":album:" + id
+	if dir, ok := b.cache.Get(cacheKey); ok {
+		return dir, nil
+	}
+
 	al, tracks, err := b.retrieveAlbum(id)
 	if err != nil {
 		return nil, err
 	}
 	log.Debug(ctx, "Found Album", "id", id, "name", al.Name)
+
 	var mfIds []string
 	for _, mf := range tracks {
 		mfIds = append(mfIds, mf.ID)
 	}
-
-	userID := getUserID(ctx)
 	trackAnnMap, err := b.ds.Annotation().GetMap(userID, model.MediaItemType, mfIds)
 	if err != nil {
 		return nil, err
 	}
-	ann, err := b.ds.Annotation().Get(userID, model.AlbumItemType, al.ID)
+	albumAnn, err := b.ds.Annotation().Get(userID, model.AlbumItemType, al.ID)
 	if err != nil {
 		return nil, err
 	}
-	return b.buildAlbumDir(al, ann, tracks, trackAnnMap), nil
+
+	dir := b.buildAlbumDir(al, albumAnn, tracks, trackAnnMap)
+	b.cache.Put(cacheKey, dir)
+	return dir, nil
 }
 
 func (b *browser) Directory(ctx context.Context, id string) (*DirectoryInfo, error) {
 	switch {
+	case isSmartId(id):
+		return b.smartDirectory(ctx, id)
 	case b.isArtist(ctx, id):
 		return b.Artist(ctx, id)
 	case b.isAlbum(ctx, id):
@@ -136,6 +202,25 @@ func (b *browser) GetSong(ctx context.Context, id string) (*Entry, error) {
 	return &entry, nil
 }
 
+// InvalidateSong drops the caller's cached Artist/Album entries for the
+// album/artist a song belongs to. The scrobble path calls this after
+// registering a play, since InternalTarget.Scrobble updates that user's
+// play-count annotation for the track out from under whatever
+// Artist/Album response is sitting in the cache - without this, a user's own
+// directory view would keep serving stale annotation data for up to the
+// cache's TTL after every play.
+func (b *browser) InvalidateSong(ctx context.Context, id string) error {
+	mf, err := b.ds.MediaFile().Get(id)
+	if err != nil {
+		return err
+	}
+
+	userID := getUserID(ctx)
+	b.cache.Delete(userID + ":album:" + mf.AlbumID)
+	b.cache.Delete(userID + ":artist:" + mf.ArtistID)
+	return nil
+}
+
 func (b *browser) GetGenres(ctx context.Context) (model.Genres, error) {
 	genres, err := b.ds.Genre().GetAll()
 	for i, g := range genres {
@@ -149,6 +234,9 @@ func (b *browser) GetGenres(ctx context.Context) (model.Genres, error) {
 	return genres, err
 }
 
+// buildArtistDir builds the DirectoryInfo from albums plus the caller's
+// user-scoped annotation map (Browser.Artist loads it via a single
+// Annotation().GetMap call, not per album).
 func (b *browser) buildArtistDir(a *model.Artist, albums model.Albums, albumAnnMap model.AnnotationMap) *DirectoryInfo {
 	dir := &DirectoryInfo{
 		Id:         a.ID,
@@ -165,6 +253,9 @@ func (b *browser) buildArtistDir(a *model.Artist, albums model.Albums, albumAnnM
 	return dir
 }
 
+// buildAlbumDir builds the DirectoryInfo from al/tracks plus the caller's
+// user-scoped annotations (Browser.Album loads them via a single
+// Annotation().GetMap/Get call, not per track).
 func (b *browser) buildAlbumDir(al *model.Album, albumAnn *model.Annotation, tracks model.MediaFiles, trackAnnMap model.AnnotationMap) *DirectoryInfo {
 	dir := &DirectoryInfo{
 		Id:        al.ID,
@@ -187,8 +278,7 @@ func (b *browser) buildAlbumDir(al *model.Album, albumAnn *model.Annotation, tra
 
 	dir.Entries = make(Entries, len(tracks))
 	for i, mf := range tracks {
-		mfId := mf.ID
-		ann := trackAnnMap[mfId]
+		ann := trackAnnMap[mf.ID]
 		dir.Entries[i] = FromMediaFile(&mf, &ann)
 	}
 	return dir
@@ -237,3 +327,86 @@ func (b *browser) retrieveAlbum(id string) (al *model.Album, mfs model.MediaFile
 	}
 	return
 }
+
+func (b *browser) ArtistInfo(ctx context.Context, id string) (*ArtistInfo, error) {
+	a, err := b.ds.Artist().Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading Artist %s from DB: %v", id, err)
+	}
+
+	info, err := b.agents.GetArtistInfo(ctx, a.ID, a.Name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ArtistInfo{
+		Id:             a.ID,
+		Biography:      info.Biography,
+		MusicBrainzId:  info.MBID,
+		LastFmUrl:      info.LastFMUrl,
+		SmallImageUrl:  info.SmallImageUrl,
+		MediumImageUrl: info.MediumImageUrl,
+		LargeImageUrl:  info.LargeImageUrl,
+	}
+	for _, sa := range info.SimilarArtists {
+		result.SimilarArtist = append(result.SimilarArtist, DirectoryInfo{Name: sa.Name})
+	}
+	return result, nil
+}
+
+func (b *browser) AlbumInfo(ctx context.Context, id string) (*AlbumInfo, error) {
+	al, err := b.ds.Album().Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading Album %s from DB: %v", id, err)
+	}
+
+	info, err := b.agents.GetAlbumInfo(ctx, al.ID, al.Name, al.Artist, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &AlbumInfo{
+		Id:             al.ID,
+		Notes:          info.Description,
+		MusicBrainzId:  info.MBID,
+		SmallImageUrl:  info.SmallImageUrl,
+		MediumImageUrl: info.MediumImageUrl,
+		LargeImageUrl:  info.LargeImageUrl,
+	}, nil
+}
+
+// SimilarSongs returns up to count random tracks from the given artist's
+// catalog. It is a starting point for real "similar artist" resolution,
+// which requires matching agents.SimilarArtist results back to local
+// artists once a search-by-name DAL method is available.
+func (b *browser) SimilarSongs(ctx context.Context, id string, count int) (Entries, error) {
+	_, albums, err := b.retrieveArtist(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var pool model.MediaFiles
+	for _, al := range albums {
+		tracks, err := b.ds.MediaFile().FindByAlbum(al.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading %s's tracks from DB: %v", al.Name, err)
+		}
+		pool = append(pool, tracks...)
+	}
+
+	rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	if len(pool) > count {
+		pool = pool[:count]
+	}
+
+	userID := getUserID(ctx)
+	entries := make(Entries, len(pool))
+	for i, mf := range pool {
+		ann, err := b.ds.Annotation().Get(userID, model.MediaItemType, mf.ID)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = FromMediaFile(&mf, ann)
+	}
+	return entries, nil
+}
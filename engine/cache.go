@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/deluan/navidrome/model"
+)
+
+// DirectoryCache is an in-process LRU cache of DirectoryInfo results,
+// scoped per (user, id) so Artist/Album/Directory don't redo their DAL
+// fan-out on back-to-back Subsonic calls (a single client screen often
+// triggers several). It is invalidated wholesale whenever the scanner
+// publishes a ScanEvent, since a new scan can change any entry.
+type DirectoryCache struct {
+	maxEntries int
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+
+	hits, misses uint64
+}
+
+type dirCacheEntry struct {
+	key       string
+	value     *DirectoryInfo
+	expiresAt time.Time
+}
+
+func NewDirectoryCache(maxEntries int, ttl time.Duration, bus *model.ScanEventBus) *DirectoryCache {
+	c := &DirectoryCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		ll:         list.New(),
+		items:      map[string]*list.Element{},
+	}
+	if bus != nil {
+		go c.invalidateOnScan(bus.Subscribe())
+	}
+	return c
+}
+
+func (c *DirectoryCache) invalidateOnScan(events <-chan model.ScanEvent) {
+	for range events {
+		c.Clear()
+	}
+}
+
+func (c *DirectoryCache) Get(key string) (*DirectoryInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	entry := el.Value.(*dirCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	atomic.AddUint64(&c.hits, 1)
+	return entry.value, true
+}
+
+func (c *DirectoryCache) Put(key string, value *DirectoryInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*dirCacheEntry).value = value
+		el.Value.(*dirCacheEntry).expiresAt = time.Now().Add(c.ttl)
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&dirCacheEntry{key: key, value: value, expiresAt: time.Now().Add(c.ttl)})
+	c.items[key] = el
+
+	for c.ll.Len() > c.maxEntries {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*dirCacheEntry).key)
+	}
+}
+
+// Delete removes a single entry, if present. Used to invalidate one user's
+// cached Artist/Album view when the data it was built from changes (e.g. a
+// scrobble updating that user's play-count annotation), without discarding
+// every other entry the way Clear does.
+func (c *DirectoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return
+	}
+	c.ll.Remove(el)
+	delete(c.items, key)
+}
+
+func (c *DirectoryCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	c.items = map[string]*list.Element{}
+}
+
+// Metrics returns the cumulative hit/miss counts, for the /admin/metrics
+// Prometheus endpoint.
+func (c *DirectoryCache) Metrics() (hits, misses uint64) {
+	return atomic.LoadUint64(&c.hits), atomic.LoadUint64(&c.misses)
+}
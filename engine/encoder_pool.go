@@ -0,0 +1,27 @@
+package engine
+
+import "context"
+
+// EncoderPool bounds how many ffmpeg processes can run concurrently, so a
+// burst of transcode requests doesn't exhaust the host's CPU.
+type EncoderPool struct {
+	sem chan struct{}
+}
+
+func NewEncoderPool(maxConcurrent int) *EncoderPool {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &EncoderPool{sem: make(chan struct{}, maxConcurrent)}
+}
+
+// Acquire blocks until a slot is free, or ctx is cancelled. On success it
+// returns a release func that must be called to free the slot.
+func (p *EncoderPool) Acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case p.sem <- struct{}{}:
+		return func() { <-p.sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,220 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/deluan/navidrome/log"
+	"github.com/deluan/navidrome/model"
+)
+
+// StreamJob is a single response to a `stream`/`download` request: either
+// the raw file on disk, or a pipe hooked up to a running ffmpeg process.
+type StreamJob struct {
+	ContentType string
+	// Seekable is true for the raw format: Reader is then nil and the job is
+	// the regular file at FilePath, which supports http.ServeFile's Range
+	// handling. It is false for transcodes, which are read from Reader and
+	// whose length isn't known up front.
+	Seekable bool
+	FilePath string
+	Reader   io.ReadCloser
+	// done is closed by the goroutine that owns cmd.Wait() once ffmpeg has
+	// exited, so Close can wait for that without calling Wait itself -
+	// os/exec only allows one Wait per Cmd.
+	done <-chan struct{}
+}
+
+// Close releases the resources backing the job: closing the pipe makes a
+// still-running ffmpeg see a write error (or, combined with the context
+// passed to Stream, get killed outright) and exit, at which point the
+// goroutine that started it finishes waiting.
+func (j *StreamJob) Close() error {
+	var err error
+	if j.Reader != nil {
+		err = j.Reader.Close()
+	}
+	if j.done != nil {
+		<-j.done
+	}
+	return err
+}
+
+func NewMediaStreamer(ds model.DataStore, pool *EncoderPool, cacheDir string) MediaStreamer {
+	return &mediaStreamer{ds: ds, pool: pool, cacheDir: cacheDir}
+}
+
+type mediaStreamer struct {
+	ds       model.DataStore
+	pool     *EncoderPool
+	cacheDir string
+}
+
+func (m *mediaStreamer) Stream(ctx context.Context, id, format string, maxBitRate int) (*StreamJob, error) {
+	mf, err := m.ds.MediaFile().Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading MediaFile %s from DB: %v", id, err)
+	}
+
+	if format == "raw" || format == "" && maxBitRate == 0 {
+		return &StreamJob{ContentType: mimeTypeForSuffix(mf.Suffix), Seekable: true, FilePath: mf.Path}, nil
+	}
+
+	profile, err := selectProfile(format, maxBitRate)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Format == "raw" {
+		return &StreamJob{ContentType: mimeTypeForSuffix(mf.Suffix), Seekable: true, FilePath: mf.Path}, nil
+	}
+
+	release, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := buildFFmpegCmd(ctx, profile, mf.Path)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		release()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer release()
+		defer close(done)
+		// ctx cancellation (client disconnect, seek, skip) makes
+		// CommandContext kill the process, which unblocks Wait here.
+		if err := cmd.Wait(); err != nil {
+			log.Warn(ctx, "ffmpeg transcode exited with error", "id", id, "format", format, err)
+		}
+	}()
+
+	return &StreamJob{ContentType: profile.ContentType, Seekable: false, Reader: stdout, done: done}, nil
+}
+
+// HLSPlaylist returns an on-demand m3u8 playlist for the track, segmenting
+// it into fixed-length chunks. The segments themselves aren't generated
+// until a client requests them via HLSSegment.
+func (m *mediaStreamer) HLSPlaylist(ctx context.Context, id string, maxBitRate int) (string, error) {
+	mf, err := m.ds.MediaFile().Get(id)
+	if err != nil {
+		return "", fmt.Errorf("Error reading MediaFile %s from DB: %v", id, err)
+	}
+
+	const segmentSeconds = 10
+	segments := mf.Duration/segmentSeconds + 1
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	b.WriteString("#EXT-X-VERSION:3\n")
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", segmentSeconds)
+	b.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+	for i := 0; i < segments; i++ {
+		fmt.Fprintf(&b, "#EXTINF:%d.0,\n", segmentSeconds)
+		fmt.Fprintf(&b, "hls.segment?id=%s&maxBitRate=%d&segment=%d\n", id, maxBitRate, i)
+	}
+	b.WriteString("#EXT-X-ENDLIST\n")
+	return b.String(), nil
+}
+
+// HLSSegment returns the path to the cached, ffmpeg-generated segment file,
+// producing it first if this is the first time it's been requested.
+func (m *mediaStreamer) HLSSegment(ctx context.Context, id string, maxBitRate, segment int) (string, error) {
+	mf, err := m.ds.MediaFile().Get(id)
+	if err != nil {
+		return "", fmt.Errorf("Error reading MediaFile %s from DB: %v", id, err)
+	}
+
+	profile, err := selectProfile("opus", maxBitRate)
+	if err != nil {
+		return "", err
+	}
+
+	segPath := m.segmentPath(id, profile, segment)
+	if _, err := os.Stat(segPath); err == nil {
+		return segPath, nil
+	}
+
+	release, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	if err := os.MkdirAll(filepath.Dir(segPath), 0755); err != nil {
+		return "", err
+	}
+
+	// ffmpeg writes to a sibling temp file first: if it's killed (ctx
+	// cancelled by a client disconnect) or exits with an error partway
+	// through, segPath itself is never created, so the next request's
+	// os.Stat check at the top of this method doesn't mistake a partial
+	// file for a complete, cacheable segment.
+	tmpPath := segPath + ".tmp"
+	const segmentSeconds = 10
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-ss", fmt.Sprintf("%d", segment*segmentSeconds),
+		"-t", fmt.Sprintf("%d", segmentSeconds),
+		"-i", mf.Path,
+		"-map", "0:a:0",
+		"-b:a", fmt.Sprintf("%dk", profile.BitRate),
+		"-c:a", "libopus",
+		"-f", "mpegts",
+		tmpPath,
+	)
+	if err := cmd.Run(); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("Error generating HLS segment %d for %s: %v", segment, id, err)
+	}
+	if err := os.Rename(tmpPath, segPath); err != nil {
+		return "", fmt.Errorf("Error finalizing HLS segment %d for %s: %v", segment, id, err)
+	}
+	return segPath, nil
+}
+
+// segmentPath is content-addressable on (trackID, bitrate, codec, segment)
+// so repeated requests for the same segment, even across server restarts,
+// are served from disk instead of re-invoking ffmpeg.
+func (m *mediaStreamer) segmentPath(id string, profile TranscodingProfile, segment int) string {
+	dir := filepath.Join(m.cacheDir, "hls", id, fmt.Sprintf("%s-%d", profile.Format, profile.BitRate))
+	return filepath.Join(dir, fmt.Sprintf("%05d.ts", segment))
+}
+
+func buildFFmpegCmd(ctx context.Context, profile TranscodingProfile, path string) *exec.Cmd {
+	args := strings.Fields(profile.CommandLine)
+	for i, a := range args {
+		switch a {
+		case "%s":
+			args[i] = path
+		case "%bk":
+			args[i] = fmt.Sprintf("%dk", profile.BitRate)
+		}
+	}
+	return exec.CommandContext(ctx, args[0], args[1:]...)
+}
+
+func mimeTypeForSuffix(suffix string) string {
+	switch strings.ToLower(suffix) {
+	case "mp3":
+		return "audio/mpeg"
+	case "flac":
+		return "audio/flac"
+	case "ogg", "opus":
+		return "audio/ogg"
+	case "m4a", "aac":
+		return "audio/mp4"
+	default:
+		return "application/octet-stream"
+	}
+}
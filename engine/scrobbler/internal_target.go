@@ -0,0 +1,36 @@
+package scrobbler
+
+import (
+	"context"
+	"time"
+
+	"github.com/deluan/navidrome/model"
+)
+
+// internalTargetName is InternalTarget.Name(); Scrobbler.Scrobble checks
+// against it to skip queuing InternalTarget failures for retry.
+const internalTargetName = "internal"
+
+// InternalTarget records plays as annotations in the local datastore, the
+// same play-count/last-played data already surfaced by Browser.Album and
+// Browser.Artist. A failure here means the local datastore write itself
+// failed, which a blind retry against the same datastore is unlikely to fix,
+// so Scrobbler.Scrobble reports the error but never hands InternalTarget to
+// the retry queue.
+type InternalTarget struct {
+	ds model.DataStore
+}
+
+func NewInternalTarget(ds model.DataStore) *InternalTarget {
+	return &InternalTarget{ds: ds}
+}
+
+func (t *InternalTarget) Name() string { return internalTargetName }
+
+func (t *InternalTarget) NowPlaying(ctx context.Context, userID string, track Track) error {
+	return nil
+}
+
+func (t *InternalTarget) Scrobble(ctx context.Context, userID string, track Track, playTime time.Time) error {
+	return t.ds.Annotation().IncPlayCount(userID, model.MediaItemType, track.ID, playTime)
+}
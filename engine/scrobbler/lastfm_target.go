@@ -0,0 +1,102 @@
+package scrobbler
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deluan/navidrome/log"
+	"github.com/deluan/navidrome/model"
+)
+
+const lastFMApiUrl = "https://ws.audioscrobbler.com/2.0/"
+
+// LastFMTarget scrobbles to Last.fm using a per-user session key obtained
+// out of band via auth.getMobileSession and stored in
+// model.ScrobblerCredentialsRepository under provider "lastfm".
+type LastFMTarget struct {
+	apiKey, apiSecret string
+	creds             model.ScrobblerCredentialsRepository
+	client            *http.Client
+}
+
+func NewLastFMTarget(apiKey, apiSecret string, creds model.ScrobblerCredentialsRepository) *LastFMTarget {
+	return &LastFMTarget{apiKey: apiKey, apiSecret: apiSecret, creds: creds, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (t *LastFMTarget) Name() string { return "lastfm" }
+
+func (t *LastFMTarget) NowPlaying(ctx context.Context, userID string, track Track) error {
+	return t.call(ctx, userID, "track.updateNowPlaying", track, time.Time{})
+}
+
+func (t *LastFMTarget) Scrobble(ctx context.Context, userID string, track Track, playTime time.Time) error {
+	return t.call(ctx, userID, "track.scrobble", track, playTime)
+}
+
+func (t *LastFMTarget) call(ctx context.Context, userID, method string, track Track, playTime time.Time) error {
+	cred, err := t.creds.Get(userID, "lastfm")
+	if err != nil {
+		return fmt.Errorf("no Last.fm session for user %s: %v", userID, err)
+	}
+
+	form := url.Values{}
+	form.Set("method", method)
+	form.Set("api_key", t.apiKey)
+	form.Set("sk", cred.Token)
+	form.Set("artist", track.Artist)
+	form.Set("track", track.Title)
+	form.Set("album", track.Album)
+	if !playTime.IsZero() {
+		form.Set("timestamp", strconv.FormatInt(playTime.Unix(), 10))
+	}
+	form.Set("api_sig", t.sign(form))
+	form.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, lastFMApiUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		log.Warn(ctx, "Last.fm scrobble request failed", "method", method, err)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("lastfm returned status %d for %s", resp.StatusCode, method)
+	}
+	return nil
+}
+
+// sign implements Last.fm's request signing scheme: every param except
+// format/callback, sorted by key, concatenated as key+value, plus the
+// shared secret, then MD5'd.
+func (t *LastFMTarget) sign(form url.Values) string {
+	keys := make([]string, 0, len(form))
+	for k := range form {
+		if k == "format" || k == "callback" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var raw string
+	for _, k := range keys {
+		raw += k + form.Get(k)
+	}
+	raw += t.apiSecret
+
+	sum := md5.Sum([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,103 @@
+package scrobbler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/deluan/navidrome/log"
+	"github.com/deluan/navidrome/model"
+)
+
+const listenBrainzSubmitUrl = "https://api.listenbrainz.org/1/submit-listens"
+
+// ListenBrainzTarget submits listens using a per-user API token, stored in
+// model.ScrobblerCredentialsRepository under provider "listenbrainz".
+type ListenBrainzTarget struct {
+	creds  model.ScrobblerCredentialsRepository
+	client *http.Client
+}
+
+func NewListenBrainzTarget(creds model.ScrobblerCredentialsRepository) *ListenBrainzTarget {
+	return &ListenBrainzTarget{creds: creds, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (t *ListenBrainzTarget) Name() string { return "listenbrainz" }
+
+func (t *ListenBrainzTarget) NowPlaying(ctx context.Context, userID string, track Track) error {
+	return t.submit(ctx, userID, "playing_now", track, time.Time{})
+}
+
+func (t *ListenBrainzTarget) Scrobble(ctx context.Context, userID string, track Track, playTime time.Time) error {
+	return t.submit(ctx, userID, "single", track, playTime)
+}
+
+func (t *ListenBrainzTarget) submit(ctx context.Context, userID, listenType string, track Track, playTime time.Time) error {
+	cred, err := t.creds.Get(userID, "listenbrainz")
+	if err != nil {
+		return fmt.Errorf("no ListenBrainz token for user %s: %v", userID, err)
+	}
+
+	payload := listenBrainzPayload{ListenType: listenType}
+	listen := listenBrainzListen{
+		TrackMetadata: listenBrainzTrackMetadata{
+			TrackName:   track.Title,
+			ArtistName:  track.Artist,
+			ReleaseName: track.Album,
+			AdditionalInfo: listenBrainzAdditionalInfo{
+				RecordingMBID: track.MBID,
+			},
+		},
+	}
+	if !playTime.IsZero() {
+		listen.ListenedAt = playTime.Unix()
+	}
+	payload.Payload = []listenBrainzListen{listen}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, listenBrainzSubmitUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+cred.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		log.Warn(ctx, "ListenBrainz submit-listens request failed", err)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("listenbrainz returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type listenBrainzPayload struct {
+	ListenType string               `json:"listen_type"`
+	Payload    []listenBrainzListen `json:"payload"`
+}
+
+type listenBrainzListen struct {
+	ListenedAt    int64                     `json:"listened_at,omitempty"`
+	TrackMetadata listenBrainzTrackMetadata `json:"track_metadata"`
+}
+
+type listenBrainzTrackMetadata struct {
+	TrackName      string                     `json:"track_name"`
+	ArtistName     string                     `json:"artist_name"`
+	ReleaseName    string                     `json:"release_name,omitempty"`
+	AdditionalInfo listenBrainzAdditionalInfo `json:"additional_info"`
+}
+
+type listenBrainzAdditionalInfo struct {
+	RecordingMBID string `json:"recording_mbid,omitempty"`
+}
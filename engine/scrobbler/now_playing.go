@@ -0,0 +1,67 @@
+package scrobbler
+
+import (
+	"sync"
+	"time"
+)
+
+// NowPlayingRegistry is an in-memory, per-user "what's playing right now"
+// table backing the Subsonic getNowPlaying endpoint. Entries expire after
+// ttl so a client that stops submitting now-playing updates (crash, network
+// loss) eventually drops off the list instead of appearing stuck forever.
+type NowPlayingRegistry struct {
+	ttl    time.Duration
+	mu     sync.Mutex
+	byUser map[string]NowPlayingEntry
+}
+
+func NewNowPlayingRegistry(ttl time.Duration) *NowPlayingRegistry {
+	return &NowPlayingRegistry{ttl: ttl, byUser: map[string]NowPlayingEntry{}}
+}
+
+func (r *NowPlayingRegistry) Set(userID string, track Track) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byUser[userID] = NowPlayingEntry{UserID: userID, Track: track, StartedAt: time.Now()}
+}
+
+func (r *NowPlayingRegistry) All() []NowPlayingEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]NowPlayingEntry, 0, len(r.byUser))
+	for _, e := range r.byUser {
+		if time.Since(e.StartedAt) > r.ttl {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// Sweep blocks, removing expired entries every interval, until stop is
+// closed.
+func (r *NowPlayingRegistry) Sweep(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.evictExpired()
+		}
+	}
+}
+
+func (r *NowPlayingRegistry) evictExpired() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for userID, e := range r.byUser {
+		if time.Since(e.StartedAt) > r.ttl {
+			delete(r.byUser, userID)
+		}
+	}
+}
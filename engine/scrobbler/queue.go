@@ -0,0 +1,99 @@
+package scrobbler
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/deluan/navidrome/log"
+)
+
+// maxAttempts bounds how many times Retry retries a PendingScrobble before
+// giving up on it. Without a cap, a permanently broken target (a revoked
+// Last.fm session, a bad ListenBrainz token) would retry forever and the
+// on-disk queue would grow without bound.
+const maxAttempts = 10
+
+// PendingScrobble is a scrobble that failed to reach its target and needs
+// to be retried later.
+type PendingScrobble struct {
+	Target   string
+	UserID   string
+	Track    Track
+	PlayTime time.Time
+	Attempts int
+}
+
+// RetryQueue persists failed scrobbles to a single JSON file under dir, so
+// a server restart doesn't lose plays submitted while a target (or the
+// network) was down.
+type RetryQueue struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewRetryQueue(dir string) *RetryQueue {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Error("Could not create scrobbler retry queue dir", "dir", dir, err)
+	}
+	return &RetryQueue{path: filepath.Join(dir, "pending_scrobbles.json")}
+}
+
+func (q *RetryQueue) Enqueue(p PendingScrobble) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := q.load()
+	pending = append(pending, p)
+	q.save(pending)
+}
+
+// Retry calls send for every pending scrobble, dropping it from the queue
+// on success and keeping it (with Attempts incremented) on failure, until
+// Attempts reaches maxAttempts, at which point it is dropped as well.
+func (q *RetryQueue) Retry(send func(PendingScrobble) error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := q.load()
+	var remaining []PendingScrobble
+	for _, p := range pending {
+		if err := send(p); err != nil {
+			p.Attempts++
+			if p.Attempts >= maxAttempts {
+				log.Error("Giving up on scrobble after repeated failures", "target", p.Target, "track", p.Track.Title, "attempts", p.Attempts, err)
+				continue
+			}
+			remaining = append(remaining, p)
+			continue
+		}
+	}
+	q.save(remaining)
+}
+
+func (q *RetryQueue) load() []PendingScrobble {
+	data, err := ioutil.ReadFile(q.path)
+	if err != nil {
+		return nil
+	}
+	var pending []PendingScrobble
+	if err := json.Unmarshal(data, &pending); err != nil {
+		log.Error("Could not parse scrobbler retry queue, discarding", "path", q.path, err)
+		return nil
+	}
+	return pending
+}
+
+func (q *RetryQueue) save(pending []PendingScrobble) {
+	data, err := json.Marshal(pending)
+	if err != nil {
+		log.Error("Could not marshal scrobbler retry queue", err)
+		return
+	}
+	if err := ioutil.WriteFile(q.path, data, 0644); err != nil {
+		log.Error("Could not persist scrobbler retry queue", "path", q.path, err)
+	}
+}
@@ -0,0 +1,122 @@
+// Package scrobbler implements "now playing" notifications and play
+// scrobbles, fanning out to the internal play-count annotations plus any
+// number of external targets (Last.fm, ListenBrainz).
+package scrobbler
+
+import (
+	"context"
+	"time"
+
+	"github.com/deluan/navidrome/log"
+)
+
+// Track is the subset of track metadata a Target needs to report a play.
+type Track struct {
+	ID       string
+	Title    string
+	Artist   string
+	Album    string
+	MBID     string
+	Duration int
+}
+
+// Target is a single scrobbling backend. NowPlaying and Scrobble correspond
+// to the Subsonic `scrobble` endpoint's submission=false/true cases.
+type Target interface {
+	Name() string
+	NowPlaying(ctx context.Context, userID string, track Track) error
+	Scrobble(ctx context.Context, userID string, track Track, playTime time.Time) error
+}
+
+// Scrobbler is what the scrobble and stream handlers call into on every
+// playback, with track metadata loaded through Browser.GetSong. Targets are
+// called synchronously, so a slow target delays the caller up to its HTTP
+// client's timeout. Failed scrobbles to external targets are handed to the
+// retry queue and retried later; failed now-playing updates are only logged.
+type Scrobbler struct {
+	targets    []Target
+	queue      *RetryQueue
+	nowPlaying *NowPlayingRegistry
+}
+
+func New(queue *RetryQueue, nowPlaying *NowPlayingRegistry, targets ...Target) *Scrobbler {
+	return &Scrobbler{targets: targets, queue: queue, nowPlaying: nowPlaying}
+}
+
+// Run starts the background work the retry queue and now-playing registry
+// need to actually do anything: periodically retrying queued scrobbles
+// against their target, and sweeping expired now-playing entries. It
+// blocks until ctx is cancelled, so callers should run it in its own
+// goroutine.
+func (s *Scrobbler) Run(ctx context.Context, retryInterval, sweepInterval time.Duration) {
+	go s.nowPlaying.Sweep(sweepInterval, ctx.Done())
+
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.retryPending(ctx)
+		}
+	}
+}
+
+func (s *Scrobbler) retryPending(ctx context.Context) {
+	s.queue.Retry(func(p PendingScrobble) error {
+		for _, t := range s.targets {
+			if t.Name() != p.Target {
+				continue
+			}
+			if err := t.Scrobble(ctx, p.UserID, p.Track, p.PlayTime); err != nil {
+				log.Warn(ctx, "Retry of queued scrobble failed", "target", p.Target, "track", p.Track.Title, err)
+				return err
+			}
+			return nil
+		}
+		log.Warn(ctx, "Dropping queued scrobble for unknown target", "target", p.Target, "track", p.Track.Title)
+		return nil
+	})
+}
+
+func (s *Scrobbler) NowPlaying(ctx context.Context, userID string, track Track) error {
+	s.nowPlaying.Set(userID, track)
+	for _, t := range s.targets {
+		if err := t.NowPlaying(ctx, userID, track); err != nil {
+			log.Warn(ctx, "Could not send now-playing update", "target", t.Name(), "track", track.Title, err)
+		}
+	}
+	return nil
+}
+
+func (s *Scrobbler) Scrobble(ctx context.Context, userID string, track Track, playTime time.Time) error {
+	for _, t := range s.targets {
+		if err := t.Scrobble(ctx, userID, track, playTime); err != nil {
+			if t.Name() == internalTargetName {
+				log.Error(ctx, "Internal scrobble failed, not queued for retry", "track", track.Title, err)
+				continue
+			}
+			log.Warn(ctx, "Scrobble failed, queued for retry", "target", t.Name(), "track", track.Title, err)
+			s.queue.Enqueue(PendingScrobble{
+				Target:   t.Name(),
+				UserID:   userID,
+				Track:    track,
+				PlayTime: playTime,
+			})
+		}
+	}
+	return nil
+}
+
+// NowPlayingEntry is what getNowPlaying reports for a single user.
+type NowPlayingEntry struct {
+	UserID    string
+	Track     Track
+	StartedAt time.Time
+}
+
+func (s *Scrobbler) GetNowPlaying() []NowPlayingEntry {
+	return s.nowPlaying.All()
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/deluan/navidrome/model"
+)
+
+const smartIdPrefix = "smart:"
+
+func isSmartId(id string) bool {
+	return strings.HasPrefix(id, smartIdPrefix)
+}
+
+// SmartPlaylistCompiler translates a SmartPlaylist (or one of the built-in
+// views below) into a model.QueryOptions that the DAL can run against Album.
+//
+// Built-in views, recognized by the part of the id after "smart:":
+//   recently-added   most recently added albums
+//   top-rated        highest-rated albums
+//   genre/<name>     albums tagged with <name>
+//   decade/<YYYYs>   albums released in the given decade, e.g. "1990s"
+//
+// Any other suffix is looked up as the ID of a persisted SmartPlaylist, whose
+// clauses are ANDed together into qo.Filters.
+type SmartPlaylistCompiler struct {
+	ds model.DataStore
+}
+
+func NewSmartPlaylistCompiler(ds model.DataStore) *SmartPlaylistCompiler {
+	return &SmartPlaylistCompiler{ds: ds}
+}
+
+func (c *SmartPlaylistCompiler) Compile(id string) (name string, qo model.QueryOptions, err error) {
+	key := strings.TrimPrefix(id, smartIdPrefix)
+
+	switch {
+	case key == "recently-added":
+		return "Recently Added", model.QueryOptions{Sort: "created_at", Order: "desc", Max: 100}, nil
+	case key == "top-rated":
+		return "Top Rated", model.QueryOptions{Sort: "rating", Order: "desc", Max: 100}, nil
+	case strings.HasPrefix(key, "genre/"):
+		genre := strings.TrimPrefix(key, "genre/")
+		return "Genre: " + genre, model.QueryOptions{
+			Sort:    "name",
+			Order:   "asc",
+			Filters: []model.Filter{{Field: "genre", Operator: "eq", Value: genre}},
+		}, nil
+	case strings.HasPrefix(key, "decade/"):
+		decade := strings.TrimPrefix(key, "decade/")
+		year := strings.TrimSuffix(decade, "s")
+		return "Decade: " + decade, model.QueryOptions{
+			Sort:    "year",
+			Order:   "asc",
+			Filters: []model.Filter{{Field: "decade", Operator: "eq", Value: year}},
+		}, nil
+	}
+
+	playlist, err := c.ds.SmartPlaylistRule().Get(key)
+	if err != nil {
+		return "", model.QueryOptions{}, fmt.Errorf("Error reading SmartPlaylist %s from DB: %v", key, err)
+	}
+	filters := make([]model.Filter, len(playlist.Clauses))
+	copy(filters, playlist.Clauses)
+	return playlist.Name, model.QueryOptions{
+		Sort:    playlist.Sort,
+		Order:   "asc",
+		Max:     playlist.Limit,
+		Filters: filters,
+	}, nil
+}
+
+// PlaylistInfo is a single smart playlist as listed by getPlaylists: its id
+// (the same id Compile and browser.GetPlaylist expect) and display name.
+type PlaylistInfo struct {
+	Id   string
+	Name string
+}
+
+// ListPlaylists returns the recently-added and top-rated built-in views plus
+// every persisted SmartPlaylist, for getPlaylists. The parameterized
+// genre/<name> and decade/<YYYYs> views are not listed. It only resolves ids and names - building
+// the actual directory is still Compile's job, so there's no second place
+// that knows how to turn a smart playlist id into a query.
+func (c *SmartPlaylistCompiler) ListPlaylists() ([]PlaylistInfo, error) {
+	playlists := []PlaylistInfo{
+		{Id: smartIdPrefix + "recently-added", Name: "Recently Added"},
+		{Id: smartIdPrefix + "top-rated", Name: "Top Rated"},
+	}
+
+	saved, err := c.ds.SmartPlaylistRule().GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading SmartPlaylists from DB: %v", err)
+	}
+	for _, p := range saved {
+		playlists = append(playlists, PlaylistInfo{Id: smartIdPrefix + p.ID, Name: p.Name})
+	}
+	return playlists, nil
+}
+
+// GetPlaylists lists the smart playlists ListPlaylists returns for
+// getPlaylists, each as the same id smartDirectory/GetPlaylist use.
+func (b *browser) GetPlaylists(ctx context.Context) ([]PlaylistInfo, error) {
+	return b.smartCompiler.ListPlaylists()
+}
+
+// GetPlaylist resolves a getPlaylists id to its DirectoryInfo. It's a thin
+// adapter over smartDirectory so getPlaylist doesn't duplicate the query
+// compilation smartDirectory/Directory already do for "smart:" ids.
+func (b *browser) GetPlaylist(ctx context.Context, id string) (*DirectoryInfo, error) {
+	return b.smartDirectory(ctx, id)
+}
+
+func (b *browser) smartDirectory(ctx context.Context, id string) (*DirectoryInfo, error) {
+	name, qo, err := b.smartCompiler.Compile(id)
+	if err != nil {
+		return nil, err
+	}
+
+	albums, err := b.ds.Album().GetAll(qo)
+	if err != nil {
+		return nil, fmt.Errorf("Error running smart playlist query %s: %v", id, err)
+	}
+
+	var albumIds []string
+	for _, al := range albums {
+		albumIds = append(albumIds, al.ID)
+	}
+	annMap, err := b.ds.Annotation().GetMap(getUserID(ctx), model.AlbumItemType, albumIds)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := &DirectoryInfo{Id: id, Name: name, AlbumCount: len(albums)}
+	dir.Entries = make(Entries, len(albums))
+	for i, al := range albums {
+		ann := annMap[al.ID]
+		dir.Entries[i] = FromAlbum(&al, &ann)
+	}
+	return dir, nil
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+)
+
+// TranscodingProfile describes how to turn a source file into a given
+// delivery format: the ffmpeg command line template and the bit rate it
+// targets. %s is replaced with the source path, %b with the bit rate.
+type TranscodingProfile struct {
+	Format      string
+	BitRate     int
+	CommandLine string
+	ContentType string
+}
+
+// defaultProfiles mirrors the presets most Subsonic clients ask for, so
+// `format` values the mobile clients send (opus, mp3, raw) never 404. It is
+// the only source of profiles: none are read from config.
+var defaultProfiles = map[string]TranscodingProfile{
+	"opus": {
+		Format:      "opus",
+		BitRate:     128,
+		CommandLine: "ffmpeg -i %s -map 0:a:0 -b:a %bk -vn -c:a libopus -f opus -",
+		ContentType: "audio/ogg",
+	},
+	"mp3": {
+		Format:      "mp3",
+		BitRate:     192,
+		CommandLine: "ffmpeg -i %s -map 0:a:0 -b:a %bk -vn -c:a libmp3lame -f mp3 -",
+		ContentType: "audio/mpeg",
+	},
+	"raw": {
+		Format:      "raw",
+		ContentType: "",
+	},
+}
+
+// selectProfile picks the profile for the requested format, falling back to
+// opus when the client didn't ask for anything specific. maxBitRate, when
+// greater than zero, caps the profile's bit rate.
+func selectProfile(format string, maxBitRate int) (TranscodingProfile, error) {
+	if format == "" {
+		format = "opus"
+	}
+	p, ok := defaultProfiles[format]
+	if !ok {
+		return TranscodingProfile{}, fmt.Errorf("unknown transcoding format %q", format)
+	}
+	if maxBitRate > 0 && (p.BitRate == 0 || maxBitRate < p.BitRate) {
+		p.BitRate = maxBitRate
+	}
+	return p, nil
+}
+
+// MediaStreamer produces playable audio for a track, either as the raw
+// file or transcoded on the fly, and builds on-demand HLS playlists.
+type MediaStreamer interface {
+	Stream(ctx context.Context, id, format string, maxBitRate int) (*StreamJob, error)
+	HLSPlaylist(ctx context.Context, id string, maxBitRate int) (string, error)
+	HLSSegment(ctx context.Context, id string, maxBitRate, segment int) (string, error)
+}
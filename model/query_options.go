@@ -0,0 +1,23 @@
+package model
+
+// Filter is a single "field operator value" comparison, e.g.
+// ("year", "gt", "1990"). Operator is one of "eq", "gt", "lt" or "contains";
+// an empty Operator means "eq". Multiple Filters on a QueryOptions are ANDed
+// together.
+type Filter struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+// QueryOptions refines a repository GetAll call with sorting, paging and
+// filters. It is used by callers that need ad-hoc result sets, such as the
+// smart playlist compiler, rather than a fixed finder method like
+// FindByArtist.
+type QueryOptions struct {
+	Sort    string
+	Order   string
+	Max     int
+	Offset  int
+	Filters []Filter
+}
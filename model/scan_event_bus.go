@@ -0,0 +1,45 @@
+package model
+
+import (
+	"sync"
+	"time"
+)
+
+// ScanEvent is published whenever the library scanner finishes a pass,
+// so in-process caches that derive from the DAL (e.g. engine.DirectoryCache)
+// know to drop their entries.
+type ScanEvent struct {
+	Timestamp time.Time
+}
+
+// ScanEventBus is a tiny pub/sub used to decouple the scanner from anything
+// that caches scan-derived data. Subscribers get their own buffered
+// channel; a slow subscriber never blocks Publish.
+type ScanEventBus struct {
+	mu          sync.Mutex
+	subscribers []chan ScanEvent
+}
+
+func NewScanEventBus() *ScanEventBus {
+	return &ScanEventBus{}
+}
+
+func (b *ScanEventBus) Subscribe() <-chan ScanEvent {
+	ch := make(chan ScanEvent, 1)
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, ch)
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *ScanEventBus) Publish(e ScanEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
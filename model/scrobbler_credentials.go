@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// ScrobblerCredentials holds a single user's auth material for an external
+// scrobbling target (Last.fm's session key, ListenBrainz's user token,
+// etc). The meaning of Token is target-specific.
+type ScrobblerCredentials struct {
+	UserID    string
+	Provider  string
+	Username  string
+	Token     string
+	CreatedAt time.Time
+}
+
+type ScrobblerCredentialsRepository interface {
+	Get(userID, provider string) (*ScrobblerCredentials, error)
+	Put(c *ScrobblerCredentials) error
+	Delete(userID, provider string) error
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// SmartPlaylist is a named, saved smart/virtual playlist: its filter clauses
+// (ANDed together as QueryOptions.Filters), plus a limit and sort order. A
+// SmartPlaylist is compiled into a DAL query on demand; see
+// engine.SmartPlaylistCompiler.
+type SmartPlaylist struct {
+	ID        string
+	Name      string
+	Clauses   []Filter
+	Limit     int
+	Sort      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type SmartPlaylists []SmartPlaylist
+
+type SmartPlaylistRuleRepository interface {
+	Get(id string) (*SmartPlaylist, error)
+	GetAll() (SmartPlaylists, error)
+	Put(playlist *SmartPlaylist) error
+	Delete(id string) error
+}
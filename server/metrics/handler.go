@@ -0,0 +1,52 @@
+// Package metrics exposes internal counters (currently just the Browser
+// directory cache's hit/miss rate) as a Prometheus endpoint, mounted by the
+// server at /admin/metrics.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	cacheHits = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "navidrome",
+		Subsystem: "directory_cache",
+		Name:      "hits_total",
+		Help:      "Number of Browser directory cache lookups that hit.",
+	})
+	cacheMisses = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "navidrome",
+		Subsystem: "directory_cache",
+		Name:      "misses_total",
+		Help:      "Number of Browser directory cache lookups that missed.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(cacheHits, cacheMisses)
+}
+
+// DirectoryCacheMetrics is the minimal view of engine.DirectoryCache this
+// package needs, kept separate to avoid an import cycle with engine.
+type DirectoryCacheMetrics interface {
+	Metrics() (hits, misses uint64)
+}
+
+// Observe refreshes the exported gauges from the cache's current counters.
+// The HTTP handler calls this on every scrape.
+func Observe(cache DirectoryCacheMetrics) {
+	hits, misses := cache.Metrics()
+	cacheHits.Set(float64(hits))
+	cacheMisses.Set(float64(misses))
+}
+
+func Handler(cache DirectoryCacheMetrics) http.Handler {
+	inner := promhttp.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Observe(cache)
+		inner.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,81 @@
+package subsonic
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/deluan/navidrome/engine"
+	"github.com/deluan/navidrome/server/subsonic/responses"
+)
+
+// BrowsingController serves the parts of the Subsonic API backed by
+// externally-sourced metadata: getArtistInfo, getAlbumInfo and
+// getSimilarSongs.
+type BrowsingController struct {
+	browser engine.Browser
+}
+
+func NewBrowsingController(browser engine.Browser) *BrowsingController {
+	return &BrowsingController{browser: browser}
+}
+
+func (c *BrowsingController) GetArtistInfo(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	id := r.URL.Query().Get("id")
+
+	info, err := c.browser.ArtistInfo(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := NewResponse()
+	response.ArtistInfo = &responses.ArtistInfo{
+		Biography:      info.Biography,
+		MusicBrainzId:  info.MusicBrainzId,
+		LastFmUrl:      info.LastFmUrl,
+		SmallImageUrl:  info.SmallImageUrl,
+		MediumImageUrl: info.MediumImageUrl,
+		LargeImageUrl:  info.LargeImageUrl,
+	}
+	for _, sa := range info.SimilarArtist {
+		response.ArtistInfo.SimilarArtist = append(response.ArtistInfo.SimilarArtist, responses.Artist{Name: sa.Name})
+	}
+	return response, nil
+}
+
+func (c *BrowsingController) GetAlbumInfo(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	id := r.URL.Query().Get("id")
+
+	info, err := c.browser.AlbumInfo(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := NewResponse()
+	response.AlbumInfo = &responses.AlbumInfo{
+		Notes:          info.Notes,
+		MusicBrainzId:  info.MusicBrainzId,
+		SmallImageUrl:  info.SmallImageUrl,
+		MediumImageUrl: info.MediumImageUrl,
+		LargeImageUrl:  info.LargeImageUrl,
+	}
+	return response, nil
+}
+
+func (c *BrowsingController) GetSimilarSongs(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	id := r.URL.Query().Get("id")
+	count := 50
+	if v := r.URL.Query().Get("count"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			count = n
+		}
+	}
+
+	songs, err := c.browser.SimilarSongs(r.Context(), id, count)
+	if err != nil {
+		return nil, err
+	}
+
+	response := NewResponse()
+	response.SimilarSongs = &responses.SimilarSongs{Songs: ToChildren(songs)}
+	return response, nil
+}
@@ -0,0 +1,51 @@
+package subsonic
+
+import (
+	"net/http"
+
+	"github.com/deluan/navidrome/engine"
+	"github.com/deluan/navidrome/server/subsonic/responses"
+)
+
+// PlaylistController serves getPlaylists and getPlaylist. The only
+// playlists this build knows how to produce are smart/virtual ones (see
+// engine.SmartPlaylistCompiler) - there's no separate persisted "regular
+// playlist" entity here, so both endpoints are thin adapters over Browser's
+// smart playlist support.
+type PlaylistController struct {
+	browser engine.Browser
+}
+
+func NewPlaylistController(browser engine.Browser) *PlaylistController {
+	return &PlaylistController{browser: browser}
+}
+
+func (c *PlaylistController) GetPlaylists(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	playlists, err := c.browser.GetPlaylists(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	response := NewResponse()
+	response.Playlists = &responses.Playlists{}
+	for _, p := range playlists {
+		response.Playlists.Playlist = append(response.Playlists.Playlist, responses.Playlist{Id: p.Id, Name: p.Name})
+	}
+	return response, nil
+}
+
+func (c *PlaylistController) GetPlaylist(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	id := r.URL.Query().Get("id")
+
+	dir, err := c.browser.GetPlaylist(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := NewResponse()
+	response.Playlist = &responses.PlaylistWithSongs{
+		Playlist: responses.Playlist{Id: dir.Id, Name: dir.Name, SongCount: len(dir.Entries)},
+		Entry:    ToChildren(dir.Entries),
+	}
+	return response, nil
+}
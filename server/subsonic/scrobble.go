@@ -0,0 +1,71 @@
+package subsonic
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/deluan/navidrome/engine"
+	"github.com/deluan/navidrome/engine/scrobbler"
+	"github.com/deluan/navidrome/log"
+	"github.com/deluan/navidrome/server/subsonic/responses"
+)
+
+// ScrobbleController serves `scrobble` (both the submission=false "now
+// playing" case and submission=true "play registered" case) and
+// `getNowPlaying`.
+type ScrobbleController struct {
+	browser   engine.Browser
+	scrobbler *scrobbler.Scrobbler
+}
+
+func NewScrobbleController(browser engine.Browser, s *scrobbler.Scrobbler) *ScrobbleController {
+	return &ScrobbleController{browser: browser, scrobbler: s}
+}
+
+func (c *ScrobbleController) Scrobble(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	ctx := r.Context()
+	id := r.URL.Query().Get("id")
+	submission := r.URL.Query().Get("submission") != "false"
+	userID := getUserID(ctx)
+
+	entry, err := c.browser.GetSong(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	track := scrobbler.Track{ID: entry.Id, Title: entry.Title, Artist: entry.Artist, Album: entry.Album, Duration: entry.Duration}
+
+	if !submission {
+		if err := c.scrobbler.NowPlaying(ctx, userID, track); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := c.scrobbler.Scrobble(ctx, userID, track, time.Now()); err != nil {
+			return nil, err
+		}
+		// InternalTarget.Scrobble just updated this user's play-count
+		// annotation for id, so the cached Artist/Album views it belongs to
+		// are now stale.
+		if err := c.browser.InvalidateSong(ctx, id); err != nil {
+			log.Warn(ctx, "Could not invalidate directory cache after scrobble", "id", id, err)
+		}
+	}
+
+	return NewResponse(), nil
+}
+
+func (c *ScrobbleController) GetNowPlaying(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	entries := c.scrobbler.GetNowPlaying()
+
+	response := NewResponse()
+	response.NowPlaying = &responses.NowPlaying{}
+	for _, e := range entries {
+		response.NowPlaying.Entry = append(response.NowPlaying.Entry, responses.NowPlayingEntry{
+			UserName: e.UserID,
+			Title:    e.Track.Title,
+			Artist:   e.Track.Artist,
+			Album:    e.Track.Album,
+			MinutesAgo: int(time.Since(e.StartedAt).Minutes()),
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,109 @@
+package subsonic
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/deluan/navidrome/engine"
+	"github.com/deluan/navidrome/engine/scrobbler"
+	"github.com/deluan/navidrome/log"
+	"github.com/deluan/navidrome/server/subsonic/responses"
+)
+
+// StreamController serves playable audio: `stream`/`download` (raw or
+// transcoded, with Range support for the raw case) and the on-demand HLS
+// endpoints `hls.m3u8`/`hls.segment`. `stream` (but not `download`) also
+// reports a now-playing update, since it's the endpoint clients actually
+// use for playback.
+type StreamController struct {
+	browser   engine.Browser
+	streamer  engine.MediaStreamer
+	scrobbler *scrobbler.Scrobbler
+}
+
+func NewStreamController(browser engine.Browser, streamer engine.MediaStreamer, s *scrobbler.Scrobbler) *StreamController {
+	return &StreamController{browser: browser, streamer: streamer, scrobbler: s}
+}
+
+func (c *StreamController) Stream(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	return c.serve(w, r, false)
+}
+
+func (c *StreamController) Download(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	return c.serve(w, r, true)
+}
+
+func (c *StreamController) serve(w http.ResponseWriter, r *http.Request, download bool) (*responses.Subsonic, error) {
+	id := r.URL.Query().Get("id")
+	format := r.URL.Query().Get("format")
+	maxBitRate, _ := strconv.Atoi(r.URL.Query().Get("maxBitRate"))
+	if download {
+		format = "raw"
+	}
+
+	job, err := c.streamer.Stream(r.Context(), id, format, maxBitRate)
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+
+	if !download {
+		c.reportNowPlaying(r, id)
+	}
+
+	w.Header().Set("Content-Type", job.ContentType)
+	if job.Seekable {
+		http.ServeFile(w, r, job.FilePath)
+		return nil, nil
+	}
+
+	w.Header().Set("Accept-Ranges", "none")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, job.Reader); err != nil {
+		log.Warn(r.Context(), "Error streaming transcoded audio", "id", id, err)
+	}
+	return nil, nil
+}
+
+func (c *StreamController) reportNowPlaying(r *http.Request, id string) {
+	ctx := r.Context()
+	entry, err := c.browser.GetSong(ctx, id)
+	if err != nil {
+		log.Warn(ctx, "Could not load song for now-playing update", "id", id, err)
+		return
+	}
+	track := scrobbler.Track{ID: entry.Id, Title: entry.Title, Artist: entry.Artist, Album: entry.Album, Duration: entry.Duration}
+	if err := c.scrobbler.NowPlaying(ctx, getUserID(ctx), track); err != nil {
+		log.Warn(ctx, "Could not update now-playing status", "id", id, err)
+	}
+}
+
+func (c *StreamController) GetHLSPlaylist(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	id := r.URL.Query().Get("id")
+	maxBitRate, _ := strconv.Atoi(r.URL.Query().Get("maxBitRate"))
+
+	playlist, err := c.streamer.HLSPlaylist(r.Context(), id, maxBitRate)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.Write([]byte(playlist))
+	return nil, nil
+}
+
+func (c *StreamController) GetHLSSegment(w http.ResponseWriter, r *http.Request) (*responses.Subsonic, error) {
+	id := r.URL.Query().Get("id")
+	maxBitRate, _ := strconv.Atoi(r.URL.Query().Get("maxBitRate"))
+	segment, _ := strconv.Atoi(r.URL.Query().Get("segment"))
+
+	path, err := c.streamer.HLSSegment(r.Context(), id, maxBitRate, segment)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Header().Set("Content-Type", "video/mp2t")
+	http.ServeFile(w, r, path)
+	return nil, nil
+}